@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"database/sql"
+
+	"github.com/frallan97/fullstack-starter/backend/models"
+	"github.com/frallan97/fullstack-starter/backend/services"
+	"github.com/lib/pq"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrInvalidCredentials is returned when a username is unknown or the
+// supplied password does not match the stored hash. It is deliberately
+// identical for both cases so callers can't use it to enumerate usernames.
+var ErrInvalidCredentials = errors.New("invalid username or password")
+
+// ErrUsernameTaken is returned by Register when the requested username
+// already has a credential.
+var ErrUsernameTaken = errors.New("username already taken")
+
+// pqUniqueViolation is the Postgres SQLSTATE for a unique constraint
+// violation (23505).
+const pqUniqueViolation = "23505"
+
+// dummyPasswordHash is a bcrypt hash of no password anyone will ever
+// submit. AttemptLogin compares against it on an unknown-username path
+// so that branch costs the same bcrypt time as a real comparison,
+// instead of returning early and leaking which usernames exist through
+// response latency.
+var dummyPasswordHash = []byte("$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy")
+
+// LocalProvider authenticates users against the credentials table using
+// bcrypt-hashed passwords, for deployments that don't want to depend on
+// the external auth-service. Only bcrypt is supported for now (no
+// argon2id option); revisit if a deployment needs configurable KDF cost
+// beyond what bcrypt.DefaultCost offers.
+type LocalProvider struct{}
+
+// NewLocalProvider constructs a LocalProvider.
+func NewLocalProvider() *LocalProvider {
+	return &LocalProvider{}
+}
+
+// AttemptLogin implements LoginProvider.
+func (p *LocalProvider) AttemptLogin(username, password string) (*models.User, error) {
+	ctx := context.Background()
+
+	cred, err := services.GetCredentialByUsername(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Compare against a dummy hash anyway so this branch costs the
+		// same bcrypt time as a known username with a wrong password -
+		// otherwise response latency leaks which usernames exist.
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up credentials: %w", err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword(cred.PasswordHash, []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	user, err := services.GetUserByID(ctx, cred.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user for credential: %w", err)
+	}
+
+	return user, nil
+}
+
+// Register creates a new local user and credential, hashing password
+// with bcrypt. It is not part of the LoginProvider interface since
+// remote/OIDC deployments provision accounts out of band.
+func (p *LocalProvider) Register(username, password, email, name string) (*models.User, error) {
+	ctx := context.Background()
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user, err := services.CreateLocalUser(ctx, email, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	if _, err := services.CreateCredential(ctx, user.ID, username, passwordHash); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation {
+			return nil, ErrUsernameTaken
+		}
+		return nil, fmt.Errorf("failed to create credential: %w", err)
+	}
+
+	return user, nil
+}
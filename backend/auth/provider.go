@@ -0,0 +1,31 @@
+// Package auth defines the pluggable login/OAuth provider abstraction
+// that lets the starter authenticate users without hard-depending on the
+// external auth-service.
+package auth
+
+import (
+	"context"
+
+	"github.com/frallan97/fullstack-starter/backend/models"
+)
+
+// UserInfoFields holds the subset of an OIDC userinfo response the
+// starter cares about when reconciling a remote identity to a local user.
+type UserInfoFields struct {
+	Email         string
+	EmailVerified bool
+	Name          string
+	Picture       string
+}
+
+// LoginProvider authenticates a user from a username/password pair.
+type LoginProvider interface {
+	AttemptLogin(username, password string) (*models.User, error)
+}
+
+// OAuthProvider authenticates a user that has already completed an
+// external OAuth/OIDC exchange, given the issuer-asserted subject and
+// whatever userinfo claims the issuer returned.
+type OAuthProvider interface {
+	AttemptLogin(ctx context.Context, subject string, userinfo UserInfoFields) (*models.User, error)
+}
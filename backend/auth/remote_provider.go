@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/frallan97/fullstack-starter/backend/models"
+	"github.com/frallan97/fullstack-starter/backend/services"
+	"github.com/google/uuid"
+)
+
+// RemoteProvider authenticates against the external auth-service's own
+// username/password login endpoint, for deployments that still run that
+// service as the source of truth for credentials.
+type RemoteProvider struct {
+	AuthServiceURL string
+	client         *http.Client
+}
+
+// NewRemoteProvider constructs a RemoteProvider targeting authServiceURL.
+func NewRemoteProvider(authServiceURL string) *RemoteProvider {
+	return &RemoteProvider{
+		AuthServiceURL: authServiceURL,
+		client:         &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type remoteLoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type remoteLoginResponse struct {
+	UserID uuid.UUID `json:"user_id"`
+	Email  string    `json:"email"`
+	Name   string    `json:"name"`
+}
+
+// AttemptLogin implements LoginProvider by delegating the credential
+// check to the auth-service and syncing the result into the local users
+// table, the same way the JWT middleware already does for bearer tokens.
+func (p *RemoteProvider) AttemptLogin(username, password string) (*models.User, error) {
+	body, err := json.Marshal(remoteLoginRequest{Username: username, Password: password})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode login request: %w", err)
+	}
+
+	resp, err := p.client.Post(p.AuthServiceURL+"/api/login", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach auth-service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrInvalidCredentials
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("auth-service login failed: status %d", resp.StatusCode)
+	}
+
+	var loginResp remoteLoginResponse
+	if err := json.NewDecoder(resp.Body).Decode(&loginResp); err != nil {
+		return nil, fmt.Errorf("failed to decode auth-service response: %w", err)
+	}
+
+	user, err := services.CreateOrUpdateUser(context.Background(), loginResp.UserID, loginResp.Email, loginResp.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sync user from auth-service: %w", err)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+	"time"
+)
+
+// stateTTL bounds how long an OAuth CSRF state token stays valid while
+// the user is off completing the provider's consent screen.
+const stateTTL = 10 * time.Minute
+
+type stateEntry struct {
+	provider  string
+	expiresAt time.Time
+}
+
+var (
+	stateMu    sync.Mutex
+	stateStore = map[string]stateEntry{}
+)
+
+// NewState mints a random CSRF state token for provider and remembers it
+// until it is consumed or expires.
+func NewState(provider string) string {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	state := base64.RawURLEncoding.EncodeToString(buf)
+
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	stateStore[state] = stateEntry{provider: provider, expiresAt: time.Now().Add(stateTTL)}
+
+	return state
+}
+
+// ConsumeState reports whether state is a valid, unexpired token
+// previously minted for provider, removing it so it can't be replayed.
+func ConsumeState(provider, state string) bool {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	entry, ok := stateStore[state]
+	delete(stateStore, state)
+	if !ok {
+		return false
+	}
+
+	return entry.provider == provider && time.Now().Before(entry.expiresAt)
+}
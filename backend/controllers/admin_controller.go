@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/frallan97/fullstack-starter/backend/services"
+	"github.com/frallan97/fullstack-starter/backend/services/refresh"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type suspendUserRequest struct {
+	Notice string `json:"notice"`
+}
+
+// SuspendUser marks the user identified by {id} as suspended, recording
+// the notice shown to them.
+func SuspendUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req suspendUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := services.SuspendUser(r.Context(), userID, req.Notice); err != nil {
+		http.Error(w, "Failed to suspend user", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ConfirmUser marks the user identified by {id} as confirmed and active.
+func ConfirmUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := services.ConfirmUser(r.Context(), userID); err != nil {
+		http.Error(w, "Failed to confirm user", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeSessions revokes every refresh token family belonging to the
+// user identified by {id}, so none of their refresh tokens can be
+// rotated again. Session cookies and already-issued access tokens are
+// not revoked.
+func RevokeSessions(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := refresh.RevokeAllForUser(r.Context(), userID); err != nil {
+		http.Error(w, "Failed to revoke sessions", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
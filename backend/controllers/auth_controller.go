@@ -2,37 +2,113 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
-	"github.com/frallan97/fullstack-starter/backend/models"
+	"github.com/frallan97/fullstack-starter/backend/middleware"
+	"github.com/frallan97/fullstack-starter/backend/services"
+	"github.com/frallan97/fullstack-starter/backend/services/refresh"
 )
 
-// GetCurrentUser returns the authenticated user from context
+// GetCurrentUser returns the authenticated user from the unified AuthContext
 func GetCurrentUser(w http.ResponseWriter, r *http.Request) {
-	user, ok := r.Context().Value("user").(*models.User)
+	ac, ok := middleware.GetAuthContext(r.Context())
 	if !ok {
 		http.Error(w, "User not found in context", http.StatusUnauthorized)
 		return
 	}
 
+	user, err := services.GetUserByID(r.Context(), ac.UserID)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(user)
 }
 
-// RefreshToken handles token refresh requests
+// RefreshToken rotates the refresh token presented in the request
+// cookie, atomically revoking it and issuing a successor in the same
+// family. Reuse of an already-rotated token revokes the whole family,
+// forcing the caller to log in again.
 func RefreshToken(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(RefreshTokenCookieName)
+	if err != nil {
+		http.Error(w, "Missing refresh token", http.StatusUnauthorized)
+		return
+	}
+
+	newToken, err := refresh.Rotate(r.Context(), cookie.Value, r.UserAgent(), clientIP(r))
+	if errors.Is(err, refresh.ErrReuseDetected) {
+		clearRefreshCookie(w)
+		http.Error(w, "Refresh token reuse detected, please log in again", http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Invalid or expired refresh token", http.StatusUnauthorized)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     RefreshTokenCookieName,
+		Value:    newToken,
+		Path:     "/api/v1/auth",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotImplemented)
 	json.NewEncoder(w).Encode(map[string]string{
-		"message": "Refresh token endpoint not implemented",
+		"message": "Token refreshed",
 	})
 }
 
-// Logout handles logout requests
-func Logout(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{
-		"message": "Logged out successfully",
+// Logout handles logout requests, revoking the session and refresh
+// token family backing the presented cookies (if any).
+func Logout(cipher *services.SessionCipher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie(middleware.SessionCookieName); err == nil {
+			if sessionID, err := cipher.Decrypt(cookie.Value); err == nil {
+				if err := services.RevokeSession(r.Context(), sessionID); err != nil {
+					http.Error(w, "Failed to revoke session", http.StatusInternalServerError)
+					return
+				}
+			}
+			http.SetCookie(w, &http.Cookie{
+				Name:     middleware.SessionCookieName,
+				Value:    "",
+				Path:     "/",
+				MaxAge:   -1,
+				HttpOnly: true,
+			})
+		}
+
+		if cookie, err := r.Cookie(RefreshTokenCookieName); err == nil {
+			if familyID, err := refresh.FamilyForToken(r.Context(), cookie.Value); err == nil {
+				if err := refresh.RevokeFamily(r.Context(), familyID); err != nil {
+					http.Error(w, "Failed to revoke refresh tokens", http.StatusInternalServerError)
+					return
+				}
+			}
+			clearRefreshCookie(w)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "Logged out successfully",
+		})
+	}
+}
+
+// clearRefreshCookie expires the refresh token cookie client-side.
+func clearRefreshCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     RefreshTokenCookieName,
+		Value:    "",
+		Path:     "/api/v1/auth",
+		MaxAge:   -1,
+		HttpOnly: true,
 	})
 }
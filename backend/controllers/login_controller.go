@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/frallan97/fullstack-starter/backend/auth"
+	"github.com/frallan97/fullstack-starter/backend/services"
+)
+
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Login authenticates a username/password pair against the configured
+// LoginProvider, establishes a cookie-backed session, and returns the
+// resulting user.
+func Login(provider auth.LoginProvider, cipher *services.SessionCipher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req loginRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		user, err := provider.AttemptLogin(req.Username, req.Password)
+		if err != nil {
+			http.Error(w, "Invalid username or password", http.StatusUnauthorized)
+			return
+		}
+
+		if err := issueSessionCookie(w, r, cipher, user.ID); err != nil {
+			http.Error(w, "Failed to establish session", http.StatusInternalServerError)
+			return
+		}
+
+		if err := issueRefreshCookie(w, r, user.ID); err != nil {
+			http.Error(w, "Failed to issue refresh token", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(user)
+	}
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/frallan97/fullstack-starter/backend/auth"
+	"github.com/frallan97/fullstack-starter/backend/services"
+	"github.com/gorilla/mux"
+)
+
+// OAuthStart redirects the browser to the named provider's authorization
+// endpoint to begin the OAuth/OIDC login flow.
+func OAuthStart(providers map[string]*auth.OIDCProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := mux.Vars(r)["provider"]
+		provider, ok := providers[name]
+		if !ok {
+			http.Error(w, "Unknown OAuth provider", http.StatusNotFound)
+			return
+		}
+
+		state := auth.NewState(name)
+		http.Redirect(w, r, provider.AuthorizationURL(state), http.StatusFound)
+	}
+}
+
+// OAuthCallback completes the authorization code exchange for the named
+// provider, establishes a cookie-backed session, and logs the resulting
+// user in.
+func OAuthCallback(providers map[string]*auth.OIDCProvider, cipher *services.SessionCipher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := mux.Vars(r)["provider"]
+		provider, ok := providers[name]
+		if !ok {
+			http.Error(w, "Unknown OAuth provider", http.StatusNotFound)
+			return
+		}
+
+		state := r.URL.Query().Get("state")
+		if !auth.ConsumeState(name, state) {
+			http.Error(w, "Invalid or expired OAuth state", http.StatusBadRequest)
+			return
+		}
+
+		code := r.URL.Query().Get("code")
+		if code == "" {
+			http.Error(w, "Missing authorization code", http.StatusBadRequest)
+			return
+		}
+
+		subject, userinfo, err := provider.ExchangeCode(r.Context(), code)
+		if err != nil {
+			http.Error(w, "OAuth exchange failed", http.StatusUnauthorized)
+			return
+		}
+
+		user, err := provider.AttemptLogin(r.Context(), subject, userinfo)
+		if err != nil {
+			http.Error(w, "Login failed", http.StatusUnauthorized)
+			return
+		}
+
+		if err := issueSessionCookie(w, r, cipher, user.ID); err != nil {
+			http.Error(w, "Failed to establish session", http.StatusInternalServerError)
+			return
+		}
+
+		if err := issueRefreshCookie(w, r, user.ID); err != nil {
+			http.Error(w, "Failed to issue refresh token", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(user)
+	}
+}
@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/frallan97/fullstack-starter/backend/config"
+	"github.com/frallan97/fullstack-starter/backend/middleware"
+	"github.com/frallan97/fullstack-starter/backend/services/otp"
+	"github.com/google/uuid"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+type otpEnrollResponse struct {
+	ProvisioningURI string   `json:"provisioning_uri"`
+	QRCodePNG       []byte   `json:"qr_code_png"`
+	BackupCodes     []string `json:"backup_codes"`
+}
+
+// OTPEnroll issues a fresh, unverified TOTP secret and a new set of
+// backup codes for the authenticated user, returning a provisioning URI
+// and QR code for scanning into an authenticator app. The secret isn't
+// usable for MFA until confirmed via OTPVerify.
+func OTPEnroll(cfg *config.Config, cipher *otp.SecretCipher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac, ok := middleware.GetAuthContext(r.Context())
+		if !ok {
+			http.Error(w, "User not authenticated", http.StatusUnauthorized)
+			return
+		}
+
+		secret, err := otp.Enroll(r.Context(), cipher, ac.UserID)
+		if err != nil {
+			http.Error(w, "Failed to enroll TOTP", http.StatusInternalServerError)
+			return
+		}
+
+		codes, err := otp.GenerateBackupCodes(r.Context(), ac.UserID, 10)
+		if err != nil {
+			http.Error(w, "Failed to generate backup codes", http.StatusInternalServerError)
+			return
+		}
+
+		uri := otp.ProvisioningURI(cfg.OTPIssuer, ac.Email, secret)
+		png, err := qrcode.Encode(uri, qrcode.Medium, 256)
+		if err != nil {
+			http.Error(w, "Failed to render QR code", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(otpEnrollResponse{
+			ProvisioningURI: uri,
+			QRCodePNG:       png,
+			BackupCodes:     codes,
+		})
+	}
+}
+
+type otpCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// OTPVerify activates a pending TOTP enrollment once the user proves
+// possession of the secret with a valid code.
+func OTPVerify(cipher *otp.SecretCipher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac, ok := middleware.GetAuthContext(r.Context())
+		if !ok {
+			http.Error(w, "User not authenticated", http.StatusUnauthorized)
+			return
+		}
+
+		var req otpCodeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if !otp.Allow(ac.UserID) {
+			http.Error(w, "Too many attempts, try again later", http.StatusTooManyRequests)
+			return
+		}
+
+		secret, err := otp.GetPendingSecret(r.Context(), cipher, ac.UserID)
+		if err != nil {
+			http.Error(w, "No pending TOTP enrollment", http.StatusBadRequest)
+			return
+		}
+
+		if !otp.Verify(secret, req.Code) {
+			http.Error(w, "Invalid code", http.StatusUnauthorized)
+			return
+		}
+
+		if err := otp.Activate(r.Context(), ac.UserID); err != nil {
+			http.Error(w, "Failed to activate TOTP", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// OTPChallenge verifies a TOTP or backup code for a user who already has
+// MFA enrolled, and on success marks the current session/token as having
+// completed step-up authentication.
+func OTPChallenge(cipher *otp.SecretCipher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ac, ok := middleware.GetAuthContext(r.Context())
+		if !ok {
+			http.Error(w, "User not authenticated", http.StatusUnauthorized)
+			return
+		}
+
+		var req otpCodeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if !otp.Allow(ac.UserID) {
+			http.Error(w, "Too many attempts, try again later", http.StatusTooManyRequests)
+			return
+		}
+
+		matched, err := verifyOTPChallenge(r, cipher, ac.UserID, req.Code)
+		if err != nil {
+			http.Error(w, "TOTP not enrolled", http.StatusBadRequest)
+			return
+		}
+		if !matched {
+			http.Error(w, "Invalid code", http.StatusUnauthorized)
+			return
+		}
+
+		otp.SetMarker(middleware.MFAMarkerKey(ac))
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// verifyOTPChallenge accepts either a live TOTP code or a single-use
+// backup code.
+func verifyOTPChallenge(r *http.Request, cipher *otp.SecretCipher, userID uuid.UUID, code string) (bool, error) {
+	secret, err := otp.GetSecret(r.Context(), cipher, userID)
+	if err != nil {
+		return false, err
+	}
+	if otp.Verify(secret, code) {
+		return true, nil
+	}
+	return otp.VerifyBackupCode(r.Context(), userID, code)
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/frallan97/fullstack-starter/backend/auth"
+	"github.com/frallan97/fullstack-starter/backend/services"
+)
+
+type registerRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+}
+
+// Register provisions a new local credential and logs the resulting
+// user in, establishing the same cookie-backed session as Login. It
+// only exists for the local LoginProvider; remote/OIDC deployments
+// provision accounts out of band.
+func Register(provider *auth.LocalProvider, cipher *services.SessionCipher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req registerRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if req.Username == "" || req.Password == "" {
+			http.Error(w, "Username and password are required", http.StatusBadRequest)
+			return
+		}
+
+		user, err := provider.Register(req.Username, req.Password, req.Email, req.Name)
+		if errors.Is(err, auth.ErrUsernameTaken) {
+			http.Error(w, "Username already taken", http.StatusConflict)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Failed to register user", http.StatusInternalServerError)
+			return
+		}
+
+		if err := issueSessionCookie(w, r, cipher, user.ID); err != nil {
+			http.Error(w, "Failed to establish session", http.StatusInternalServerError)
+			return
+		}
+
+		if err := issueRefreshCookie(w, r, user.ID); err != nil {
+			http.Error(w, "Failed to issue refresh token", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(user)
+	}
+}
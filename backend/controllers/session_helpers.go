@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/frallan97/fullstack-starter/backend/middleware"
+	"github.com/frallan97/fullstack-starter/backend/services"
+	"github.com/frallan97/fullstack-starter/backend/services/refresh"
+	"github.com/google/uuid"
+)
+
+// RefreshTokenCookieName is the cookie the refresh flow reads and login
+// flows should write. It's scoped to the auth path prefix since it's
+// only ever needed by /auth/refresh and /auth/logout.
+const RefreshTokenCookieName = "refresh_token"
+
+// issueSessionCookie creates a session for userID and sets the encrypted
+// session cookie on the response, so a successful login also establishes
+// a cookie-backed session for browser clients.
+func issueSessionCookie(w http.ResponseWriter, r *http.Request, cipher *services.SessionCipher, userID uuid.UUID) error {
+	session, err := services.CreateSession(r.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	cookieValue, err := cipher.Encrypt(session.ID)
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.SessionCookieName,
+		Value:    cookieValue,
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return nil
+}
+
+// issueRefreshCookie mints a refresh token family for userID and sets
+// it on the response, so a successful login also lets the client rotate
+// that refresh token later via /auth/refresh without re-authenticating.
+// /auth/refresh does not issue an access token.
+func issueRefreshCookie(w http.ResponseWriter, r *http.Request, userID uuid.UUID) error {
+	token, err := refresh.Issue(r.Context(), userID, r.UserAgent(), clientIP(r))
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     RefreshTokenCookieName,
+		Value:    token,
+		Path:     "/api/v1/auth",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return nil
+}
+
+// clientIP extracts the caller's address for audit purposes, preferring
+// a forwarding header set by a reverse proxy over the raw remote address.
+func clientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+	return r.RemoteAddr
+}
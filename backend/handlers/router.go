@@ -4,15 +4,27 @@ import (
 	"net/http"
 
 	"github.com/casbin/casbin/v2"
+	"github.com/frallan97/fullstack-starter/backend/auth"
 	"github.com/frallan97/fullstack-starter/backend/config"
 	"github.com/frallan97/fullstack-starter/backend/controllers"
 	"github.com/frallan97/fullstack-starter/backend/middleware"
+	"github.com/frallan97/fullstack-starter/backend/models"
+	"github.com/frallan97/fullstack-starter/backend/services"
+	"github.com/frallan97/fullstack-starter/backend/services/otp"
 	"github.com/gorilla/mux"
 )
 
 // SetupRouter configures all routes
 // IMPORTANT: All routes include OPTIONS method for CORS preflight requests
-func SetupRouter(cfg *config.Config, enforcer *casbin.Enforcer) http.Handler {
+func SetupRouter(
+	cfg *config.Config,
+	enforcer *casbin.Enforcer,
+	loginProvider auth.LoginProvider,
+	oauthProviders map[string]*auth.OIDCProvider,
+	keys middleware.KeySet,
+	sessionCipher *services.SessionCipher,
+	otpCipher *otp.SecretCipher,
+) http.Handler {
 	r := mux.NewRouter()
 
 	// Global middleware - CORS must be first!
@@ -31,19 +43,45 @@ func SetupRouter(cfg *config.Config, enforcer *casbin.Enforcer) http.Handler {
 
 	// Auth endpoints (public except /me)
 	api.HandleFunc("/auth/refresh", controllers.RefreshToken).Methods("POST", "OPTIONS")
-	api.HandleFunc("/auth/logout", controllers.Logout).Methods("POST", "OPTIONS")
+	api.HandleFunc("/auth/logout", controllers.Logout(sessionCipher)).Methods("POST", "OPTIONS")
+	api.HandleFunc("/auth/login", controllers.Login(loginProvider, sessionCipher)).Methods("POST", "OPTIONS")
+	// Registration only exists for the local username/password provider;
+	// remote/OIDC deployments provision accounts out of band.
+	if localProvider, ok := loginProvider.(*auth.LocalProvider); ok {
+		api.HandleFunc("/auth/register", controllers.Register(localProvider, sessionCipher)).Methods("POST", "OPTIONS")
+	}
+	api.HandleFunc("/auth/oauth/{provider}/start", controllers.OAuthStart(oauthProviders)).Methods("GET", "OPTIONS")
+	api.HandleFunc("/auth/oauth/{provider}/callback", controllers.OAuthCallback(oauthProviders, sessionCipher)).Methods("GET", "OPTIONS")
 
-	// Protected routes requiring authentication
+	// Protected routes requiring authentication - accepts a bearer JWT,
+	// an encrypted session cookie, or an internal shared-secret header
 	protected := api.PathPrefix("").Subrouter()
-	protected.Use(middleware.Auth(cfg.JWTPublicKey))
+	protected.Use(middleware.Any(
+		middleware.JWTAuthenticator(keys),
+		middleware.CookieAuthenticator(sessionCipher),
+		middleware.InternalAuthenticator(cfg.InternalAuthSecret),
+	))
 
 	// Auth /me endpoint (authenticated)
 	protected.HandleFunc("/auth/me", controllers.GetCurrentUser).Methods("GET", "OPTIONS")
 
+	// TOTP step-up enrollment/verification (challenge issues the MFA marker
+	// RequireMFA checks; it doesn't itself require a marker to call)
+	protected.HandleFunc("/auth/otp/enroll", controllers.OTPEnroll(cfg, otpCipher)).Methods("POST", "OPTIONS")
+	protected.HandleFunc("/auth/otp/verify", controllers.OTPVerify(otpCipher)).Methods("POST", "OPTIONS")
+	protected.HandleFunc("/auth/otp/challenge", controllers.OTPChallenge(otpCipher)).Methods("POST", "OPTIONS")
+
 	// Protected + Authorized routes
 	authorized := protected.PathPrefix("").Subrouter()
 	authorized.Use(middleware.Authorize(enforcer))
 
+	// Admin-only user lifecycle management
+	admin := authorized.PathPrefix("/admin").Subrouter()
+	admin.Use(middleware.RequireUserType(string(models.UserTypeAdmin)))
+	admin.HandleFunc("/users/{id}/suspend", controllers.SuspendUser).Methods("POST", "OPTIONS")
+	admin.HandleFunc("/users/{id}/confirm", controllers.ConfirmUser).Methods("POST", "OPTIONS")
+	admin.HandleFunc("/users/{id}/revoke-sessions", controllers.RevokeSessions).Methods("POST", "OPTIONS")
+
 	// TODO: Add your application routes here
 	// Example:
 	// authorized.HandleFunc("/resource", controllers.GetResource).Methods("GET", "OPTIONS")
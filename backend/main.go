@@ -6,9 +6,13 @@ import (
 
 	"github.com/casbin/casbin/v2"
 	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"github.com/frallan97/fullstack-starter/backend/auth"
 	"github.com/frallan97/fullstack-starter/backend/config"
 	"github.com/frallan97/fullstack-starter/backend/database"
 	"github.com/frallan97/fullstack-starter/backend/handlers"
+	"github.com/frallan97/fullstack-starter/backend/pkg/jwks"
+	"github.com/frallan97/fullstack-starter/backend/services"
+	"github.com/frallan97/fullstack-starter/backend/services/otp"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -54,8 +58,48 @@ func main() {
 
 	log.Println("Casbin enforcer initialized successfully")
 
+	// Fetch the auth-service's signing keys and keep them fresh in the background
+	keySet := jwks.NewClient(cfg.JWKSURL)
+	if err := keySet.Start(); err != nil {
+		log.Fatalf("Failed to fetch JWKS: %v", err)
+	}
+	defer keySet.Stop()
+
+	// Pick the LoginProvider backing /auth/login
+	var loginProvider auth.LoginProvider
+	switch cfg.LoginProviderKind {
+	case "remote":
+		loginProvider = auth.NewRemoteProvider(cfg.AuthServiceURL)
+	default:
+		loginProvider = auth.NewLocalProvider()
+	}
+
+	// Discover and register whichever OIDC providers are configured
+	oauthProviders := make(map[string]*auth.OIDCProvider)
+	for name, providerCfg := range cfg.OIDCProviders {
+		provider, err := auth.NewOIDCProvider(name, providerCfg.Issuer, providerCfg.ClientID, providerCfg.ClientSecret, providerCfg.RedirectURL)
+		if err != nil {
+			log.Printf("Warning: failed to initialize %s OIDC provider: %v", name, err)
+			continue
+		}
+		oauthProviders[name] = provider
+	}
+
+	// Session cookies are AES-256-GCM encrypted so their contents are opaque
+	// to the client; the key comes from config so it survives restarts when set
+	sessionCipher, err := services.NewSessionCipher(cfg.SessionEncryptionKey)
+	if err != nil {
+		log.Fatalf("Failed to initialize session cipher: %v", err)
+	}
+
+	// TOTP secrets are likewise encrypted at rest under their own key
+	otpCipher, err := otp.NewSecretCipher(cfg.OTPEncryptionKey)
+	if err != nil {
+		log.Fatalf("Failed to initialize OTP cipher: %v", err)
+	}
+
 	// Setup router with auth
-	router := handlers.SetupRouter(cfg, enforcer)
+	router := handlers.SetupRouter(cfg, enforcer, loginProvider, oauthProviders, keySet, sessionCipher, otpCipher)
 
 	// Start server
 	addr := ":" + cfg.Port
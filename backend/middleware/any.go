@@ -0,0 +1,28 @@
+package middleware
+
+import "net/http"
+
+// Authenticator attempts to authenticate a request by one specific
+// method, returning the resulting AuthContext or an error if this
+// method doesn't apply (missing header/cookie, bad signature, etc.).
+type Authenticator func(r *http.Request) (*AuthContext, error)
+
+// Any composes several authenticators so a request is authenticated if
+// ANY of them succeeds, tried in the order given. This lets an endpoint
+// accept, say, a bearer JWT, a session cookie, or an internal
+// shared-secret header interchangeably.
+func Any(authenticators ...Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, authenticate := range authenticators {
+				ac, err := authenticate(r)
+				if err == nil {
+					next.ServeHTTP(w, r.WithContext(WithAuthContext(r.Context(), ac)))
+					return
+				}
+			}
+
+			http.Error(w, "Authentication required", http.StatusUnauthorized)
+		})
+	}
+}
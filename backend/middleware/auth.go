@@ -1,85 +1,108 @@
 package middleware
 
 import (
-	"context"
-	"crypto/rsa"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strings"
 
-	customJWT "github.com/frallan97/nordic-options-hub/backend/pkg/jwt"
-	"github.com/frallan97/nordic-options-hub/backend/services"
+	customJWT "github.com/frallan97/fullstack-starter/backend/pkg/jwt"
+	"github.com/frallan97/fullstack-starter/backend/services"
 	"github.com/google/uuid"
 )
 
-type contextKey string
+// KeySet is the subset of jwks.Client the JWT authenticator depends on:
+// resolving a kid to a verification key, and forcing a refresh when a kid
+// isn't found (e.g. right after the auth-service rotates its signing key).
+type KeySet interface {
+	customJWT.KeySet
+	ForceRefresh() error
+}
 
-const (
-	UserIDKey contextKey = "userID"
-	EmailKey  contextKey = "email"
-	NameKey   contextKey = "name"
-)
+// ErrInactiveAccount is returned by JWTAuthenticator when the bearer
+// token is otherwise valid but the synced local user has been deactivated.
+var ErrInactiveAccount = errors.New("user account is inactive")
 
-// Auth is a middleware that validates JWT tokens and adds user info to context
-func Auth(publicKey *rsa.PublicKey) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
-				http.Error(w, "Missing authorization header", http.StatusUnauthorized)
-				return
-			}
+// JWTAuthenticator validates a bearer JWT from the Authorization header
+// and syncs the claimed user to the local database.
+func JWTAuthenticator(keys KeySet) Authenticator {
+	return func(r *http.Request) (*AuthContext, error) {
+		authHeader := r.Header.Get("Authorization")
+		if authHeader == "" {
+			return nil, fmt.Errorf("missing authorization header")
+		}
 
-			parts := strings.SplitN(authHeader, " ", 2)
-			if len(parts) != 2 || parts[0] != "Bearer" {
-				http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
-				return
-			}
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return nil, fmt.Errorf("invalid authorization header format")
+		}
 
-			tokenString := parts[1]
-			claims, err := customJWT.ValidateAccessToken(tokenString, publicKey)
-			if err != nil {
-				http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
-				return
+		tokenString := parts[1]
+		claims, err := customJWT.ValidateAccessToken(tokenString, keys)
+		if errors.Is(err, customJWT.ErrUnknownKey) {
+			// The auth-service may have rotated its signing key since our
+			// last fetch; force a (rate-limited) refresh and retry once
+			// before giving up.
+			if refreshErr := keys.ForceRefresh(); refreshErr == nil {
+				claims, err = customJWT.ValidateAccessToken(tokenString, keys)
 			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid or expired token: %w", err)
+		}
 
-			// Sync user to local database (create or update)
-			user, err := services.CreateOrUpdateUser(r.Context(), claims.UserID, claims.Email, claims.Name)
-			if err != nil {
-				// Log error but don't fail - user data in JWT is sufficient
-				// This provides graceful degradation if DB has issues
-				log.Printf("[AUTH] Failed to sync user %s: %v", claims.UserID, err)
-			}
+		// The auth-service mints a unique "jti" per access token so that
+		// per-token state (MFA step-up markers, revocation) can key off a
+		// specific token rather than colliding across every session a user
+		// holds.
+		tokenID, err := uuid.Parse(claims.RegisteredClaims.ID)
+		if err != nil {
+			return nil, fmt.Errorf("token missing jti: %w", err)
+		}
 
-			// Check if user is active (only if sync succeeded)
-			if user != nil && !user.IsActive {
-				http.Error(w, "User account is inactive", http.StatusForbidden)
-				return
-			}
+		// Sync user to local database (create or update)
+		user, err := services.CreateOrUpdateUser(r.Context(), claims.UserID, claims.Email, claims.Name)
+		if err != nil {
+			// Log error but don't fail - user data in JWT is sufficient
+			// This provides graceful degradation if DB has issues
+			log.Printf("[AUTH] Failed to sync user %s: %v", claims.UserID, err)
+		}
 
-			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
-			ctx = context.WithValue(ctx, EmailKey, claims.Email)
-			ctx = context.WithValue(ctx, NameKey, claims.Name)
+		// Check if user is active (only if sync succeeded)
+		if user != nil && !user.IsActive {
+			return nil, ErrInactiveAccount
+		}
 
-			next.ServeHTTP(w, r.WithContext(ctx))
-		})
+		return &AuthContext{
+			UserID:     claims.UserID,
+			Email:      claims.Email,
+			Name:       claims.Name,
+			AuthMethod: AuthMethodJWT,
+			Grants:     ParseGrants(claims.Scope),
+			TokenID:    tokenID,
+		}, nil
 	}
 }
 
-// GetUserID extracts user ID from request context
-func GetUserID(ctx context.Context) (uuid.UUID, bool) {
-	userID, ok := ctx.Value(UserIDKey).(uuid.UUID)
-	return userID, ok
-}
+// Auth is a middleware that validates JWT tokens and adds the resulting
+// AuthContext to the request context.
+func Auth(keys KeySet) func(http.Handler) http.Handler {
+	authenticate := JWTAuthenticator(keys)
 
-// GetEmail extracts email from request context
-func GetEmail(ctx context.Context) (string, bool) {
-	email, ok := ctx.Value(EmailKey).(string)
-	return email, ok
-}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ac, err := authenticate(r)
+			if errors.Is(err, ErrInactiveAccount) {
+				http.Error(w, "User account is inactive", http.StatusForbidden)
+				return
+			}
+			if err != nil {
+				http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+				return
+			}
 
-// GetName extracts name from request context
-func GetName(ctx context.Context) (string, bool) {
-	name, ok := ctx.Value(NameKey).(string)
-	return name, ok
+			next.ServeHTTP(w, r.WithContext(WithAuthContext(r.Context(), ac)))
+		})
+	}
 }
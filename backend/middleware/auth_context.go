@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// AuthMethod identifies which middleware established the AuthContext for
+// a request.
+type AuthMethod string
+
+const (
+	AuthMethodJWT      AuthMethod = "AUTH_JWT"
+	AuthMethodCookie   AuthMethod = "AUTH_COOKIE"
+	AuthMethodInternal AuthMethod = "AUTH_INTERNAL"
+	AuthMethodWebhook  AuthMethod = "AUTH_WEBHOOK"
+)
+
+// AuthContext describes the authenticated principal for a request,
+// regardless of which auth method established it.
+type AuthContext struct {
+	UserID     uuid.UUID
+	Email      string
+	Name       string
+	AuthMethod AuthMethod
+	Grants     GrantSet
+	TokenID    uuid.UUID
+}
+
+type authContextKey struct{}
+
+// WithAuthContext returns a copy of ctx carrying ac.
+func WithAuthContext(ctx context.Context, ac *AuthContext) context.Context {
+	return context.WithValue(ctx, authContextKey{}, ac)
+}
+
+// GetAuthContext extracts the AuthContext set by one of the Auth*
+// middlewares, if any.
+func GetAuthContext(ctx context.Context) (*AuthContext, bool) {
+	ac, ok := ctx.Value(authContextKey{}).(*AuthContext)
+	return ac, ok
+}
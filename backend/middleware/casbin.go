@@ -4,29 +4,71 @@ import (
 	"net/http"
 
 	"github.com/casbin/casbin/v2"
+	"github.com/frallan97/fullstack-starter/backend/services"
 )
 
 // Authorize is a middleware that checks authorization using Casbin
 func Authorize(enforcer *casbin.Enforcer) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Get user ID from context (set by Auth middleware)
-			_, ok := GetUserID(r.Context())
+			// Get the authenticated principal from context (set by Auth, CookieAuth, ...)
+			ac, ok := GetAuthContext(r.Context())
 			if !ok {
 				http.Error(w, "User not authenticated", http.StatusUnauthorized)
 				return
 			}
 
+			// Internal service-to-service calls have no local user record,
+			// so they get a dedicated subject instead of a UserType lookup
+			subject := "internal"
+			if ac.AuthMethod != AuthMethodInternal {
+				user, err := services.GetUserByID(r.Context(), ac.UserID)
+				if err != nil {
+					http.Error(w, "User not found", http.StatusUnauthorized)
+					return
+				}
+				subject = string(user.UserType)
+			}
+
 			// Check authorization with Casbin
-			// Subject: "user" role (all authenticated users)
+			// Subject: the user's lifecycle state (active, active_paying, admin, ...)
 			// Object: request path
 			// Action: HTTP method
-			allowed, err := enforcer.Enforce("user", r.URL.Path, r.Method)
+			allowed, err := enforcer.Enforce(subject, r.URL.Path, r.Method)
 			if err != nil {
 				http.Error(w, "Authorization error", http.StatusInternalServerError)
 				return
 			}
 
+			// A universal grant (e.g. InternalAuthenticator's "*") overrides
+			// a Casbin denial rather than being enforced as a literal
+			// subject, which would otherwise require a "p, *, <path>,
+			// <method>" policy row per route to have any effect. Casbin is
+			// still consulted above, and an enforcer error still fails the
+			// request.
+			if !allowed && ac.Grants.Has("*") {
+				allowed = true
+			}
+
+			// A per-token grant satisfying the same policy also authorizes
+			// the request (e.g. "p, items:write, /api/v1/items, POST"), so
+			// narrowly-scoped machine tokens work without a dedicated
+			// Casbin role per client. The enforcer is invoked once per grant.
+			for _, grant := range ac.Grants {
+				if allowed {
+					break
+				}
+				if grant == "*" {
+					continue
+				}
+				grantAllowed, err := enforcer.Enforce(grant, r.URL.Path, r.Method)
+				if err != nil {
+					http.Error(w, "Authorization error", http.StatusInternalServerError)
+					return
+				}
+				allowed = grantAllowed
+			}
+
 			if !allowed {
 				http.Error(w, "Forbidden: insufficient permissions", http.StatusForbidden)
 				return
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/frallan97/fullstack-starter/backend/services"
+)
+
+// SessionCookieName is the cookie CookieAuth reads and login flows should write.
+const SessionCookieName = "session"
+
+// CookieAuthenticator validates the encrypted session cookie against the
+// sessions table and loads the owning user.
+func CookieAuthenticator(cipher *services.SessionCipher) Authenticator {
+	return func(r *http.Request) (*AuthContext, error) {
+		cookie, err := r.Cookie(SessionCookieName)
+		if err != nil {
+			return nil, fmt.Errorf("missing session cookie")
+		}
+
+		sessionID, err := cipher.Decrypt(cookie.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid session cookie: %w", err)
+		}
+
+		session, err := services.GetActiveSession(r.Context(), sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("session not found or expired: %w", err)
+		}
+
+		user, err := services.GetUserByID(r.Context(), session.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load session user: %w", err)
+		}
+
+		return &AuthContext{
+			UserID:     user.ID,
+			Email:      user.Email,
+			Name:       user.Name,
+			AuthMethod: AuthMethodCookie,
+			TokenID:    session.ID,
+		}, nil
+	}
+}
+
+// CookieAuth is a middleware that authenticates requests via an encrypted
+// session cookie, for browser clients that can't (or shouldn't) hold a
+// bearer JWT in JS-accessible storage.
+func CookieAuth(cipher *services.SessionCipher) func(http.Handler) http.Handler {
+	authenticate := CookieAuthenticator(cipher)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ac, err := authenticate(r)
+			if err != nil {
+				http.Error(w, "Invalid or expired session", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithAuthContext(r.Context(), ac)))
+		})
+	}
+}
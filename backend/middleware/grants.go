@@ -0,0 +1,50 @@
+package middleware
+
+import "strings"
+
+// GrantSet is a parsed, queryable set of OAuth2-style scopes (e.g.
+// "items:read", "items:*", "*"), as carried by AuthContext.Grants.
+type GrantSet []string
+
+// ParseGrants splits a space-separated scope string, as found in a
+// JWT's "scope" claim, into a GrantSet.
+func ParseGrants(scope string) GrantSet {
+	return GrantSet(strings.Fields(scope))
+}
+
+// Has reports whether the set grants scope, honoring wildcards: "*"
+// grants everything, and a "items:*" grant covers any "items:..." scope.
+func (g GrantSet) Has(scope string) bool {
+	for _, grant := range g {
+		if grant == "*" || grant == scope {
+			return true
+		}
+		if strings.HasSuffix(grant, ":*") {
+			prefix := strings.TrimSuffix(grant, "*")
+			if strings.HasPrefix(scope, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasAny reports whether the set grants at least one of scopes.
+func (g GrantSet) HasAny(scopes ...string) bool {
+	for _, scope := range scopes {
+		if g.Has(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAll reports whether the set grants every one of scopes.
+func (g GrantSet) HasAll(scopes ...string) bool {
+	for _, scope := range scopes {
+		if !g.Has(scope) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/frallan97/fullstack-starter/backend/services/otp"
+)
+
+// RequireMFA gates a route behind a completed TOTP step-up challenge,
+// independent of which primary auth method established the
+// AuthContext. A caller without a live marker gets a 401 with
+// WWW-Authenticate: OTP so the frontend knows to prompt for a code.
+func RequireMFA() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ac, ok := GetAuthContext(r.Context())
+			if !ok {
+				http.Error(w, "User not authenticated", http.StatusUnauthorized)
+				return
+			}
+
+			if !otp.HasMarker(MFAMarkerKey(ac)) {
+				w.Header().Set("WWW-Authenticate", "OTP")
+				http.Error(w, "MFA challenge required", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// MFAMarkerKey identifies the session/token an MFA marker is cached
+// against, so a step-up completed in one session can't be replayed by
+// another (e.g. stolen cookie on a different device).
+func MFAMarkerKey(ac *AuthContext) string {
+	return ac.UserID.String() + ":" + ac.TokenID.String()
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import "net/http"
+
+// RequireScope gates a route behind the caller's token carrying every
+// one of the given OAuth2-style scopes (see GrantSet for wildcard
+// matching), 403ing if any is missing.
+func RequireScope(scopes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ac, ok := GetAuthContext(r.Context())
+			if !ok {
+				http.Error(w, "User not authenticated", http.StatusUnauthorized)
+				return
+			}
+
+			if !ac.Grants.HasAll(scopes...) {
+				http.Error(w, "Forbidden: missing required scope", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
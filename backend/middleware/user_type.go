@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/frallan97/fullstack-starter/backend/services"
+)
+
+// RequireUserType restricts a route to users whose UserType is one of
+// the given values (e.g. "admin", "active_paying"). A user who doesn't
+// qualify gets a 403 with their suspension notice, if any, so clients
+// can surface why they were denied.
+func RequireUserType(types ...string) func(http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(types))
+	for _, t := range types {
+		allowed[t] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ac, ok := GetAuthContext(r.Context())
+			if !ok {
+				http.Error(w, "User not authenticated", http.StatusUnauthorized)
+				return
+			}
+
+			user, err := services.GetUserByID(r.Context(), ac.UserID)
+			if err != nil {
+				http.Error(w, "User not found", http.StatusUnauthorized)
+				return
+			}
+
+			if !allowed[string(user.UserType)] {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"error":             "account does not have the required status",
+					"suspension_notice": user.SuspensionNotice,
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is a server-side record of an issued refresh token. The
+// raw token is never stored, only its SHA-256 hash, and the family_id
+// links every token minted across successive rotations so the whole
+// chain can be revoked at once if reuse is detected.
+type RefreshToken struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	TokenHash  []byte     `json:"-"`
+	FamilyID   uuid.UUID  `json:"family_id"`
+	IssuedAt   time.Time  `json:"issued_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	ReplacedBy *uuid.UUID `json:"replaced_by,omitempty"`
+	UserAgent  *string    `json:"user_agent,omitempty"`
+	IP         *string    `json:"ip,omitempty"`
+}
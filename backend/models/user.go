@@ -6,14 +6,31 @@ import (
 	"github.com/google/uuid"
 )
 
+// UserType is the lifecycle state of a user account, used both to gate
+// access (middleware.RequireUserType) and as the Casbin subject so
+// policies can distinguish paying vs free vs admin.
+type UserType string
+
+const (
+	UserTypeUnconfirmed  UserType = "unconfirmed"
+	UserTypeActive       UserType = "active"
+	UserTypeActivePaying UserType = "active_paying"
+	UserTypeDelinquent   UserType = "delinquent"
+	UserTypeSuspended    UserType = "suspended"
+	UserTypeAdmin        UserType = "admin"
+)
+
 // User represents a user synced from auth-service
 type User struct {
-	ID        uuid.UUID  `json:"id"`
-	Email     string     `json:"email"`
-	Name      string     `json:"name"`
-	GoogleID  *string    `json:"google_id,omitempty"`
-	AvatarURL *string    `json:"avatar_url,omitempty"`
-	IsActive  bool       `json:"is_active"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
+	ID               uuid.UUID  `json:"id"`
+	Email            string     `json:"email"`
+	Name             string     `json:"name"`
+	GoogleID         *string    `json:"google_id,omitempty"`
+	AvatarURL        *string    `json:"avatar_url,omitempty"`
+	IsActive         bool       `json:"is_active"`
+	UserType         UserType   `json:"user_type"`
+	SuspensionNotice *string    `json:"suspension_notice,omitempty"`
+	ConfirmedAt      *time.Time `json:"confirmed_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
 }
@@ -0,0 +1,238 @@
+// Package jwks implements a client for the auth-service's JWKS endpoint,
+// caching public keys by "kid" and refreshing them in the background so
+// key rotation on the issuer side doesn't require a restart here.
+package jwks
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	customJWT "github.com/frallan97/fullstack-starter/backend/pkg/jwt"
+)
+
+// defaultMaxAge is used when the JWKS response has no Cache-Control
+// max-age directive.
+const defaultMaxAge = 15 * time.Minute
+
+// minForceInterval bounds how often ForceRefresh will actually hit the
+// network, so a client presenting a bogus kid over and over can't turn
+// every request into a JWKS fetch.
+const minForceInterval = 30 * time.Second
+
+// jitterFraction is the maximum fraction of the refresh interval added as
+// jitter, to keep many instances from refreshing in lockstep.
+const jitterFraction = 0.2
+
+// Client fetches and caches a JSON Web Key Set, keyed by "kid".
+type Client struct {
+	url        string
+	httpClient *http.Client
+
+	mu     sync.RWMutex
+	keys   map[string]interface{} // kid -> *rsa.PublicKey | *ecdsa.PublicKey
+	maxAge time.Duration
+
+	forceMu    sync.Mutex
+	lastForced time.Time
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// NewClient constructs a Client for the JWKS document at url. Call Start
+// to begin background refreshing.
+func NewClient(url string) *Client {
+	return &Client{
+		url:        url,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		keys:       make(map[string]interface{}),
+		maxAge:     defaultMaxAge,
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// Start fetches the key set once synchronously, then refreshes it in the
+// background until Stop is called.
+func (c *Client) Start() error {
+	if err := c.refresh(); err != nil {
+		return err
+	}
+
+	go c.refreshLoop()
+	return nil
+}
+
+// Stop ends the background refresh loop.
+func (c *Client) Stop() {
+	c.stopOnce.Do(func() { close(c.stopCh) })
+}
+
+// LookupKey implements jwt.KeySet.
+func (c *Client) LookupKey(kid string) (interface{}, error) {
+	c.mu.RLock()
+	key, ok := c.keys[kid]
+	c.mu.RUnlock()
+
+	if !ok {
+		return nil, customJWT.ErrUnknownKey
+	}
+
+	return key, nil
+}
+
+// ForceRefresh refreshes the key set immediately, ignoring the normal
+// background schedule. It's rate-limited to minForceInterval so repeated
+// calls (e.g. from requests presenting an unknown kid) can't be used to
+// hammer the auth-service.
+func (c *Client) ForceRefresh() error {
+	c.forceMu.Lock()
+	if time.Since(c.lastForced) < minForceInterval {
+		c.forceMu.Unlock()
+		return nil
+	}
+	c.lastForced = time.Now()
+	c.forceMu.Unlock()
+
+	return c.refresh()
+}
+
+func (c *Client) refreshLoop() {
+	for {
+		c.mu.RLock()
+		interval := c.maxAge
+		c.mu.RUnlock()
+
+		select {
+		case <-time.After(jitter(interval)):
+		case <-c.stopCh:
+			return
+		}
+
+		// Keep serving the last known-good key set on failure; the
+		// next tick (or a forced refresh) will try again.
+		_ = c.refresh()
+	}
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+func (c *Client) refresh() error {
+	resp, err := c.httpClient.Get(c.url)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS: status %d", resp.StatusCode)
+	}
+
+	var set jwkSet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+
+	c.mu.Lock()
+	c.keys = keys
+	c.maxAge = maxAgeFromHeader(resp.Header.Get("Cache-Control"))
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBase64URLBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA modulus for kid %q: %w", k.Kid, err)
+		}
+		e, err := decodeBase64URLBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA exponent for kid %q: %w", k.Kid, err)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported EC curve %q for kid %q", k.Crv, k.Kid)
+		}
+		x, err := decodeBase64URLBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC x for kid %q: %w", k.Kid, err)
+		}
+		y, err := decodeBase64URLBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC y for kid %q: %w", k.Kid, err)
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q for kid %q", k.Kty, k.Kid)
+	}
+}
+
+func decodeBase64URLBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// maxAgeFromHeader parses the max-age directive out of a Cache-Control
+// header value, falling back to defaultMaxAge if absent or malformed.
+func maxAgeFromHeader(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+		if !strings.HasPrefix(directive, "max-age=") {
+			continue
+		}
+		seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+		if err != nil || seconds <= 0 {
+			continue
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultMaxAge
+}
+
+// jitter returns d adjusted by up to +/- jitterFraction, so that many
+// instances refreshing on the same schedule don't all hit the
+// auth-service at once.
+func jitter(d time.Duration) time.Duration {
+	delta := time.Duration(float64(d) * jitterFraction)
+	offset := time.Duration(rand.Int63n(int64(2*delta+1))) - delta
+	return d + offset
+}
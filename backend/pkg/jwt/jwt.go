@@ -1,7 +1,7 @@
 package jwt
 
 import (
-	"crypto/rsa"
+	"errors"
 	"fmt"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -13,17 +13,42 @@ type Claims struct {
 	UserID uuid.UUID `json:"sub"`
 	Email  string    `json:"email"`
 	Name   string    `json:"name"`
+	// Scope holds space-separated OAuth2-style scopes (e.g.
+	// "items:read items:write admin:users") granted to this token,
+	// layered on top of the user's role-based Casbin permissions.
+	Scope string `json:"scope"`
 	jwt.RegisteredClaims
 }
 
-// ValidateAccessToken validates a JWT access token using RSA-256 public key
-func ValidateAccessToken(tokenString string, publicKey *rsa.PublicKey) (*Claims, error) {
+// KeySet resolves a JWT "kid" header to the public key that should verify
+// tokens signed with it. Implementations may hand back an *rsa.PublicKey
+// (RS256) or an *ecdsa.PublicKey (ES256).
+type KeySet interface {
+	LookupKey(kid string) (interface{}, error)
+}
+
+// ErrUnknownKey is returned by a KeySet when asked for a kid it does not
+// currently hold, distinct from a token simply failing verification, so
+// callers can decide whether a key-set refresh might help.
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// ValidateAccessToken validates a JWT access token, looking up the
+// verification key by the token's "kid" header in keys. Both RS256 and
+// ES256 signing methods are accepted.
+func ValidateAccessToken(tokenString string, keys KeySet) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// Verify signing method is RS256
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		default:
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return publicKey, nil
+
+		kid, ok := token.Header["kid"].(string)
+		if !ok || kid == "" {
+			return nil, fmt.Errorf("token is missing kid header")
+		}
+
+		return keys.LookupKey(kid)
 	})
 
 	if err != nil {
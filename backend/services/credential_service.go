@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/frallan97/fullstack-starter/backend/database"
+	"github.com/frallan97/fullstack-starter/backend/models"
+	"github.com/google/uuid"
+)
+
+// GetCredentialByUsername fetches a local credential record by username.
+func GetCredentialByUsername(ctx context.Context, username string) (*models.Credential, error) {
+	query := `
+        SELECT id, user_id, username, password_hash, created_at, updated_at
+        FROM credentials
+        WHERE username = $1
+    `
+
+	var cred models.Credential
+	err := database.DB.QueryRowContext(ctx, query, username).Scan(
+		&cred.ID, &cred.UserID, &cred.Username, &cred.PasswordHash,
+		&cred.CreatedAt, &cred.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("credential not found: %w", sql.ErrNoRows)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch credential: %w", err)
+	}
+
+	return &cred, nil
+}
+
+// CreateCredential inserts a new local credential record for an existing user.
+func CreateCredential(ctx context.Context, userID uuid.UUID, username string, passwordHash []byte) (*models.Credential, error) {
+	query := `
+        INSERT INTO credentials (id, user_id, username, password_hash, updated_at)
+        VALUES (gen_random_uuid(), $1, $2, $3, NOW())
+        RETURNING id, user_id, username, password_hash, created_at, updated_at
+    `
+
+	var cred models.Credential
+	err := database.DB.QueryRowContext(ctx, query, userID, username, passwordHash).Scan(
+		&cred.ID, &cred.UserID, &cred.Username, &cred.PasswordHash,
+		&cred.CreatedAt, &cred.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create credential: %w", err)
+	}
+
+	return &cred, nil
+}
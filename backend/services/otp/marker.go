@@ -0,0 +1,45 @@
+package otp
+
+import (
+	"sync"
+	"time"
+)
+
+// mfaMarkerTTL is how long a completed MFA challenge remains valid
+// before the caller has to step up again.
+const mfaMarkerTTL = 15 * time.Minute
+
+type markerEntry struct {
+	expiresAt time.Time
+}
+
+var (
+	markerMu    sync.Mutex
+	markerStore = map[string]markerEntry{}
+)
+
+// SetMarker records that key (a session/token identifier) has just
+// completed an MFA challenge ("amr=mfa"), valid for mfaMarkerTTL.
+func SetMarker(key string) {
+	markerMu.Lock()
+	defer markerMu.Unlock()
+	markerStore[key] = markerEntry{expiresAt: time.Now().Add(mfaMarkerTTL)}
+}
+
+// HasMarker reports whether key has a live MFA marker, evicting it if
+// it has expired.
+func HasMarker(key string) bool {
+	markerMu.Lock()
+	defer markerMu.Unlock()
+
+	entry, ok := markerStore[key]
+	if !ok {
+		return false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(markerStore, key)
+		return false
+	}
+
+	return true
+}
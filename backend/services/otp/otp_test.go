@@ -0,0 +1,62 @@
+package otp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerify_AcceptsCurrentAndAdjacentSteps(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+
+	now := time.Now()
+	for _, drift := range []int{0, -1, 1} {
+		code := generateCode(secret, now.Add(time.Duration(drift)*period))
+		if !Verify(secret, code) {
+			t.Errorf("Verify() with drift %d = false, want true", drift)
+		}
+	}
+}
+
+func TestVerify_RejectsOutsideDriftWindow(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+
+	code := generateCode(secret, time.Now().Add(2*period))
+	if Verify(secret, code) {
+		t.Error("Verify() with drift 2 = true, want false")
+	}
+}
+
+func TestVerify_RejectsMalformedCode(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+
+	for _, code := range []string{"", "12345", "1234567", "abcdef"} {
+		if Verify(secret, code) {
+			t.Errorf("Verify(%q) = true, want false", code)
+		}
+	}
+}
+
+func TestVerify_RejectsWrongSecret(t *testing.T) {
+	secretA, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+	secretB, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+
+	code := generateCode(secretA, time.Now())
+	if Verify(secretB, code) {
+		t.Error("Verify() accepted a code generated for a different secret")
+	}
+}
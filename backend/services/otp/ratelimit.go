@@ -0,0 +1,50 @@
+package otp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxAttempts is how many verification attempts a user gets within
+// attemptsWindow before Allow starts refusing, to defeat brute force
+// against the 6-digit code space.
+const (
+	maxAttempts    = 5
+	attemptsWindow = 5 * time.Minute
+)
+
+type attemptWindow struct {
+	count     int
+	windowEnd time.Time
+}
+
+var (
+	attemptsMu sync.Mutex
+	attempts   = map[string]*attemptWindow{}
+)
+
+// Allow reports whether userID may make another TOTP/backup code
+// verification attempt right now, counting this call towards its quota
+// if so. The counter resets once attemptsWindow has elapsed.
+func Allow(userID uuid.UUID) bool {
+	attemptsMu.Lock()
+	defer attemptsMu.Unlock()
+
+	key := userID.String()
+	now := time.Now()
+
+	w, ok := attempts[key]
+	if !ok || now.After(w.windowEnd) {
+		w = &attemptWindow{windowEnd: now.Add(attemptsWindow)}
+		attempts[key] = w
+	}
+
+	if w.count >= maxAttempts {
+		return false
+	}
+
+	w.count++
+	return true
+}
@@ -0,0 +1,173 @@
+package otp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/frallan97/fullstack-starter/backend/database"
+	"github.com/google/uuid"
+)
+
+// Enroll generates a fresh TOTP secret for userID and stores it
+// encrypted and unverified, replacing any prior (pending or active)
+// enrollment. The plaintext secret is returned once so the caller can
+// build a provisioning URI/QR code; it is never stored unencrypted.
+func Enroll(ctx context.Context, cipher *SecretCipher, userID uuid.UUID) (string, error) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		return "", err
+	}
+
+	encrypted, err := cipher.Encrypt(secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt TOTP secret: %w", err)
+	}
+
+	query := `
+        INSERT INTO user_otp (user_id, secret, verified_at, created_at, updated_at)
+        VALUES ($1, $2, NULL, NOW(), NOW())
+        ON CONFLICT (user_id) DO UPDATE
+        SET secret = EXCLUDED.secret, verified_at = NULL, updated_at = NOW()
+    `
+	if _, err := database.DB.ExecContext(ctx, query, userID, encrypted); err != nil {
+		return "", fmt.Errorf("failed to store TOTP secret: %w", err)
+	}
+
+	return secret, nil
+}
+
+// Activate marks userID's pending TOTP secret as verified, allowing it
+// to be used for MFA challenges from now on.
+func Activate(ctx context.Context, userID uuid.UUID) error {
+	query := `UPDATE user_otp SET verified_at = NOW(), updated_at = NOW() WHERE user_id = $1`
+	res, err := database.DB.ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to activate TOTP: %w", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return fmt.Errorf("no pending TOTP enrollment for user")
+	}
+	return nil
+}
+
+// GetPendingSecret loads and decrypts userID's TOTP secret regardless of
+// verification state, for use by the enrollment verify step.
+func GetPendingSecret(ctx context.Context, cipher *SecretCipher, userID uuid.UUID) (string, error) {
+	encrypted, _, err := fetchSecret(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return cipher.Decrypt(encrypted)
+}
+
+// GetSecret loads and decrypts userID's TOTP secret, failing unless
+// enrollment has been verified, for use by MFA challenges.
+func GetSecret(ctx context.Context, cipher *SecretCipher, userID uuid.UUID) (string, error) {
+	encrypted, verifiedAt, err := fetchSecret(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if verifiedAt == nil {
+		return "", fmt.Errorf("TOTP enrollment not yet verified")
+	}
+	return cipher.Decrypt(encrypted)
+}
+
+func fetchSecret(ctx context.Context, userID uuid.UUID) (encrypted string, verifiedAt *time.Time, err error) {
+	query := `SELECT secret, verified_at FROM user_otp WHERE user_id = $1`
+	err = database.DB.QueryRowContext(ctx, query, userID).Scan(&encrypted, &verifiedAt)
+	if err == sql.ErrNoRows {
+		return "", nil, fmt.Errorf("TOTP not enrolled: %w", sql.ErrNoRows)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to fetch TOTP secret: %w", err)
+	}
+	return encrypted, verifiedAt, nil
+}
+
+// GenerateBackupCodes replaces userID's backup codes with n freshly
+// generated ones, returning the plaintext codes to show the user once;
+// only their SHA-256 hashes are persisted.
+func GenerateBackupCodes(ctx context.Context, userID uuid.UUID, n int) ([]string, error) {
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM otp_backup_codes WHERE user_id = $1`, userID); err != nil {
+		return nil, fmt.Errorf("failed to clear old backup codes: %w", err)
+	}
+
+	codes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		code, err := generateBackupCode()
+		if err != nil {
+			return nil, err
+		}
+
+		query := `INSERT INTO otp_backup_codes (id, user_id, code_hash) VALUES (gen_random_uuid(), $1, $2)`
+		if _, err := tx.ExecContext(ctx, query, userID, hashBackupCode(code)); err != nil {
+			return nil, fmt.Errorf("failed to store backup code: %w", err)
+		}
+
+		codes = append(codes, code)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit backup codes: %w", err)
+	}
+
+	return codes, nil
+}
+
+// VerifyBackupCode checks code against userID's remaining backup codes,
+// deleting the matched row in the same transaction so each code is
+// single-use.
+func VerifyBackupCode(ctx context.Context, userID uuid.UUID, code string) (bool, error) {
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id uuid.UUID
+	query := `SELECT id FROM otp_backup_codes WHERE user_id = $1 AND code_hash = $2`
+	err = tx.QueryRowContext(ctx, query, userID, hashBackupCode(code)).Scan(&id)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to look up backup code: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM otp_backup_codes WHERE id = $1`, id); err != nil {
+		return false, fmt.Errorf("failed to consume backup code: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit backup code use: %w", err)
+	}
+
+	return true, nil
+}
+
+func generateBackupCode() (string, error) {
+	raw := make([]byte, 5)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate backup code: %w", err)
+	}
+	return strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw)), nil
+}
+
+func hashBackupCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,206 @@
+// Package refresh implements refresh-token issuance and rotation, so the
+// starter can manage its own session lifetime independent of whatever
+// issues the short-lived access token (the auth-service, today).
+package refresh
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/frallan97/fullstack-starter/backend/database"
+	"github.com/google/uuid"
+)
+
+// tokenTTL is how long an issued refresh token remains valid before the
+// user has to log in again via the primary auth method.
+const tokenTTL = 30 * 24 * time.Hour
+
+// ErrInvalidToken is returned when the presented refresh token doesn't
+// match any stored hash, or has expired.
+var ErrInvalidToken = errors.New("invalid refresh token")
+
+// ErrReuseDetected is returned when a refresh token that has already
+// been rotated away is presented again, which indicates the token was
+// stolen; the entire family is revoked in response.
+var ErrReuseDetected = errors.New("refresh token reuse detected")
+
+// Issue mints a brand new refresh token family for userID, returning the
+// opaque token to hand to the client. Only its SHA-256 hash is stored.
+func Issue(ctx context.Context, userID uuid.UUID, userAgent, ip string) (string, error) {
+	token, hash, err := newToken()
+	if err != nil {
+		return "", err
+	}
+
+	familyID := uuid.New()
+	query := `
+        INSERT INTO refresh_tokens (id, user_id, token_hash, family_id, issued_at, expires_at, user_agent, ip)
+        VALUES (gen_random_uuid(), $1, $2, $3, NOW(), $4, $5, $6)
+    `
+	if _, err := database.DB.ExecContext(ctx, query, userID, hash, familyID, time.Now().Add(tokenTTL), userAgent, ip); err != nil {
+		return "", fmt.Errorf("failed to issue refresh token: %w", err)
+	}
+
+	return token, nil
+}
+
+// Rotate exchanges a presented refresh token for a new one in the same
+// family, atomically revoking the old token and chaining replaced_by to
+// the successor. Reuse of an already-revoked token revokes the whole
+// family and returns ErrReuseDetected, forcing the user to log in again.
+func Rotate(ctx context.Context, presented, userAgent, ip string) (string, error) {
+	hash := hashToken(presented)
+
+	tx, err := database.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Claim the token with a single UPDATE guarded by "revoked_at IS
+	// NULL" rather than a SELECT-then-UPDATE: the row lock UPDATE takes
+	// means a second concurrent Rotate on the same token blocks until
+	// this one commits, then re-evaluates the WHERE clause and finds
+	// revoked_at already set - so only one caller can ever win the race
+	// and the other is correctly treated as reuse, instead of both
+	// reading "not yet revoked" and both minting a successor.
+	var (
+		currentID, familyID, userID uuid.UUID
+		expiresAt                   time.Time
+	)
+	claimQuery := `
+        UPDATE refresh_tokens
+        SET revoked_at = NOW()
+        WHERE token_hash = $1 AND revoked_at IS NULL
+        RETURNING id, family_id, user_id, expires_at
+    `
+	err = tx.QueryRowContext(ctx, claimQuery, hash).Scan(&currentID, &familyID, &userID, &expiresAt)
+	if err == sql.ErrNoRows {
+		// Either no token has this hash at all, or it exists but was
+		// already rotated/revoked and is being presented again - the
+		// latter is reuse, which compromises the whole family.
+		var existingFamily uuid.UUID
+		lookupErr := tx.QueryRowContext(ctx,
+			`SELECT family_id FROM refresh_tokens WHERE token_hash = $1`, hash).Scan(&existingFamily)
+		if lookupErr == sql.ErrNoRows {
+			return "", ErrInvalidToken
+		}
+		if lookupErr != nil {
+			return "", fmt.Errorf("failed to look up refresh token: %w", lookupErr)
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL`,
+			existingFamily); err != nil {
+			return "", fmt.Errorf("failed to revoke compromised family: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return "", fmt.Errorf("failed to commit family revocation: %w", err)
+		}
+		return "", ErrReuseDetected
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up refresh token: %w", err)
+	}
+
+	if time.Now().After(expiresAt) {
+		// Let the deferred Rollback undo the claim above instead of
+		// committing it: an expired token isn't reuse, so a second
+		// presentation of the same expired token must see revoked_at
+		// still NULL and take this same branch again, rather than fall
+		// into the reuse-detection path and revoke the whole family.
+		return "", ErrInvalidToken
+	}
+
+	token, newHash, err := newToken()
+	if err != nil {
+		return "", err
+	}
+
+	var newID uuid.UUID
+	insertQuery := `
+        INSERT INTO refresh_tokens (id, user_id, token_hash, family_id, issued_at, expires_at, user_agent, ip)
+        VALUES (gen_random_uuid(), $1, $2, $3, NOW(), $4, $5, $6)
+        RETURNING id
+    `
+	if err := tx.QueryRowContext(ctx, insertQuery, userID, newHash, familyID, time.Now().Add(tokenTTL), userAgent, ip).Scan(&newID); err != nil {
+		return "", fmt.Errorf("failed to issue successor token: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE refresh_tokens SET replaced_by = $2 WHERE id = $1`,
+		currentID, newID); err != nil {
+		return "", fmt.Errorf("failed to chain successor token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit rotation: %w", err)
+	}
+
+	return token, nil
+}
+
+// FamilyForToken looks up the family a presented refresh token belongs
+// to, so callers (e.g. Logout) can revoke it without needing the raw
+// family ID.
+func FamilyForToken(ctx context.Context, presented string) (uuid.UUID, error) {
+	hash := hashToken(presented)
+
+	var familyID uuid.UUID
+	err := database.DB.QueryRowContext(ctx, `SELECT family_id FROM refresh_tokens WHERE token_hash = $1`, hash).Scan(&familyID)
+	if err == sql.ErrNoRows {
+		return uuid.UUID{}, ErrInvalidToken
+	}
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("failed to look up refresh token family: %w", err)
+	}
+
+	return familyID, nil
+}
+
+// RevokeFamily revokes every still-active token in the given family, e.g.
+// in response to logout or detected reuse.
+func RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := database.DB.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL`,
+		familyID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token family: %w", err)
+	}
+	return nil
+}
+
+// RevokeAllForUser revokes every active token across every family
+// belonging to userID, e.g. for an admin-triggered "sign out everywhere".
+func RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := database.DB.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`,
+		userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke user sessions: %w", err)
+	}
+	return nil
+}
+
+// newToken generates a fresh opaque 256-bit refresh token along with the
+// SHA-256 hash that gets persisted; only the hash is ever stored.
+func newToken() (token string, hash []byte, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(raw)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
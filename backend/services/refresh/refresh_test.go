@@ -0,0 +1,126 @@
+package refresh
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/frallan97/fullstack-starter/backend/database"
+	"github.com/google/uuid"
+)
+
+// Rotate drives a real transaction against refresh_tokens (and the
+// users table it references), so this is an integration test rather
+// than a pure unit test. It's skipped unless TEST_DATABASE_URL points
+// at a migrated database.
+func testDB(t *testing.T) (uuid.UUID, func()) {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set; skipping refresh integration test")
+	}
+	if err := database.Connect(dsn); err != nil {
+		t.Fatalf("database.Connect() error = %v", err)
+	}
+
+	userID := uuid.New()
+	if _, err := database.DB.Exec(
+		`INSERT INTO users (id, email, name, user_type, updated_at) VALUES ($1, $2, 'Test User', 'active', NOW())`,
+		userID, userID.String()+"@example.com",
+	); err != nil {
+		t.Fatalf("failed to seed test user: %v", err)
+	}
+
+	return userID, func() {
+		database.DB.Exec(`DELETE FROM users WHERE id = $1`, userID)
+		database.Close()
+	}
+}
+
+func TestRotate_IssuesSuccessorInSameFamily(t *testing.T) {
+	userID, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	token, err := Issue(ctx, userID, "test-agent", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	originalFamily, err := FamilyForToken(ctx, token)
+	if err != nil {
+		t.Fatalf("FamilyForToken() error = %v", err)
+	}
+
+	next, err := Rotate(ctx, token, "test-agent", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+
+	newFamily, err := FamilyForToken(ctx, next)
+	if err != nil {
+		t.Fatalf("FamilyForToken() on successor error = %v", err)
+	}
+	if newFamily != originalFamily {
+		t.Errorf("successor family = %v, want %v", newFamily, originalFamily)
+	}
+}
+
+func TestRotate_ReuseOfRotatedTokenRevokesFamily(t *testing.T) {
+	userID, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	token, err := Issue(ctx, userID, "test-agent", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	next, err := Rotate(ctx, token, "test-agent", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+
+	// Presenting the already-rotated token again is reuse: it must be
+	// rejected and the whole family (including the successor) revoked.
+	if _, err := Rotate(ctx, token, "test-agent", "127.0.0.1"); !errors.Is(err, ErrReuseDetected) {
+		t.Fatalf("Rotate() on reused token error = %v, want ErrReuseDetected", err)
+	}
+
+	// The successor was revoked as part of the family revocation above,
+	// so presenting it is also treated as reuse rather than simply
+	// rejected as unknown.
+	if _, err := Rotate(ctx, next, "test-agent", "127.0.0.1"); !errors.Is(err, ErrReuseDetected) {
+		t.Errorf("Rotate() on revoked successor error = %v, want ErrReuseDetected", err)
+	}
+}
+
+func TestRotate_ExpiredTokenStaysInvalidOnRepeatedPresentation(t *testing.T) {
+	userID, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	token, err := Issue(ctx, userID, "test-agent", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	if _, err := database.DB.ExecContext(ctx,
+		`UPDATE refresh_tokens SET expires_at = NOW() - INTERVAL '1 minute' WHERE token_hash = $1`,
+		hashToken(token),
+	); err != nil {
+		t.Fatalf("failed to backdate token expiry: %v", err)
+	}
+
+	// An expired token isn't reuse: presenting it twice must return
+	// ErrInvalidToken both times, not fall into reuse detection (which
+	// would revoke the whole family) on the second attempt.
+	if _, err := Rotate(ctx, token, "test-agent", "127.0.0.1"); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Rotate() first attempt on expired token error = %v, want ErrInvalidToken", err)
+	}
+	if _, err := Rotate(ctx, token, "test-agent", "127.0.0.1"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Rotate() second attempt on expired token error = %v, want ErrInvalidToken", err)
+	}
+}
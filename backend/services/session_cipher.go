@@ -0,0 +1,66 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+)
+
+// SessionCipher encrypts/decrypts session cookie values with AES-256-GCM
+// so a cookie never reveals its session ID and can't be forged or
+// tampered with client-side.
+type SessionCipher struct {
+	gcm cipher.AEAD
+}
+
+// NewSessionCipher builds a SessionCipher from a 32-byte AES-256 key.
+func NewSessionCipher(key []byte) (*SessionCipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	return &SessionCipher{gcm: gcm}, nil
+}
+
+// Encrypt returns an opaque, base64url-encoded cookie value for sessionID.
+func (c *SessionCipher) Encrypt(sessionID uuid.UUID) (string, error) {
+	nonce := make([]byte, c.gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	ciphertext := c.gcm.Seal(nonce, nonce, sessionID[:], nil)
+	return base64.RawURLEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt recovers the session ID encoded in a value produced by Encrypt.
+func (c *SessionCipher) Decrypt(value string) (uuid.UUID, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("invalid cookie encoding: %w", err)
+	}
+
+	nonceSize := c.gcm.NonceSize()
+	if len(data) < nonceSize {
+		return uuid.UUID{}, fmt.Errorf("cookie value too short")
+	}
+
+	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
+	plaintext, err := c.gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("failed to decrypt cookie: %w", err)
+	}
+
+	return uuid.FromBytes(plaintext)
+}
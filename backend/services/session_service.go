@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/frallan97/fullstack-starter/backend/database"
+	"github.com/frallan97/fullstack-starter/backend/models"
+	"github.com/google/uuid"
+)
+
+// sessionTTL is how long a browser session stays valid before the user
+// has to log in again.
+const sessionTTL = 30 * 24 * time.Hour
+
+// CreateSession issues a new session row for userID.
+func CreateSession(ctx context.Context, userID uuid.UUID) (*models.Session, error) {
+	query := `
+        INSERT INTO sessions (id, user_id, expires_at)
+        VALUES (gen_random_uuid(), $1, $2)
+        RETURNING id, user_id, created_at, expires_at, revoked_at
+    `
+
+	var session models.Session
+	err := database.DB.QueryRowContext(ctx, query, userID, time.Now().Add(sessionTTL)).Scan(
+		&session.ID, &session.UserID, &session.CreatedAt, &session.ExpiresAt, &session.RevokedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// GetActiveSession fetches a session by ID, failing if it has been
+// revoked or has expired.
+func GetActiveSession(ctx context.Context, sessionID uuid.UUID) (*models.Session, error) {
+	query := `
+        SELECT id, user_id, created_at, expires_at, revoked_at
+        FROM sessions
+        WHERE id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+    `
+
+	var session models.Session
+	err := database.DB.QueryRowContext(ctx, query, sessionID).Scan(
+		&session.ID, &session.UserID, &session.CreatedAt, &session.ExpiresAt, &session.RevokedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("session not found or expired: %w", sql.ErrNoRows)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// RevokeSession marks a session as revoked so it can no longer
+// authenticate requests.
+func RevokeSession(ctx context.Context, sessionID uuid.UUID) error {
+	query := `UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+	_, err := database.DB.ExecContext(ctx, query, sessionID)
+	return err
+}
@@ -11,22 +11,26 @@ import (
 )
 
 // CreateOrUpdateUser syncs user from JWT claims to local database
-// This is called after JWT validation to ensure user exists locally
+// This is called after JWT validation to ensure user exists locally.
+// Note that user_type is only set on INSERT, never in the ON CONFLICT
+// SET clause, so an existing user's lifecycle state is preserved across
+// every sync instead of being reset to "unconfirmed" on each login.
 func CreateOrUpdateUser(ctx context.Context, userID uuid.UUID, email, name string) (*models.User, error) {
 	query := `
-        INSERT INTO users (id, email, name, updated_at)
-        VALUES ($1, $2, $3, NOW())
+        INSERT INTO users (id, email, name, user_type, updated_at)
+        VALUES ($1, $2, $3, 'unconfirmed', NOW())
         ON CONFLICT (id) DO UPDATE
         SET email = EXCLUDED.email,
             name = EXCLUDED.name,
             updated_at = NOW()
-        RETURNING id, email, name, google_id, avatar_url, is_active, created_at, updated_at
+        RETURNING id, email, name, google_id, avatar_url, is_active, user_type, suspension_notice, confirmed_at, created_at, updated_at
     `
 
 	var user models.User
 	err := database.DB.QueryRowContext(ctx, query, userID, email, name).Scan(
 		&user.ID, &user.Email, &user.Name, &user.GoogleID, &user.AvatarURL,
-		&user.IsActive, &user.CreatedAt, &user.UpdatedAt,
+		&user.IsActive, &user.UserType, &user.SuspensionNotice, &user.ConfirmedAt,
+		&user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err != nil {
@@ -36,10 +40,34 @@ func CreateOrUpdateUser(ctx context.Context, userID uuid.UUID, email, name strin
 	return &user, nil
 }
 
+// CreateLocalUser inserts a brand-new user row for a local (username/
+// password) registration, distinct from CreateOrUpdateUser which syncs
+// a user under an ID supplied by an external identity source (the
+// auth-service or an OIDC issuer).
+func CreateLocalUser(ctx context.Context, email, name string) (*models.User, error) {
+	query := `
+        INSERT INTO users (id, email, name, user_type, updated_at)
+        VALUES (gen_random_uuid(), $1, $2, 'unconfirmed', NOW())
+        RETURNING id, email, name, google_id, avatar_url, is_active, user_type, suspension_notice, confirmed_at, created_at, updated_at
+    `
+
+	var user models.User
+	err := database.DB.QueryRowContext(ctx, query, email, name).Scan(
+		&user.ID, &user.Email, &user.Name, &user.GoogleID, &user.AvatarURL,
+		&user.IsActive, &user.UserType, &user.SuspensionNotice, &user.ConfirmedAt,
+		&user.CreatedAt, &user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return &user, nil
+}
+
 // GetUserByID fetches a user by ID
 func GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
 	query := `
-        SELECT id, email, name, google_id, avatar_url, is_active, created_at, updated_at
+        SELECT id, email, name, google_id, avatar_url, is_active, user_type, suspension_notice, confirmed_at, created_at, updated_at
         FROM users
         WHERE id = $1
     `
@@ -47,7 +75,8 @@ func GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
 	var user models.User
 	err := database.DB.QueryRowContext(ctx, query, userID).Scan(
 		&user.ID, &user.Email, &user.Name, &user.GoogleID, &user.AvatarURL,
-		&user.IsActive, &user.CreatedAt, &user.UpdatedAt,
+		&user.IsActive, &user.UserType, &user.SuspensionNotice, &user.ConfirmedAt,
+		&user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -60,6 +89,29 @@ func GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
 	return &user, nil
 }
 
+// SuspendUser marks a user as suspended and records the notice shown to
+// them (e.g. by middleware.RequireUserType) when a route gated on their
+// user type denies them. Login itself does not check user_type.
+func SuspendUser(ctx context.Context, userID uuid.UUID, notice string) error {
+	query := `UPDATE users SET user_type = 'suspended', suspension_notice = $2, updated_at = NOW() WHERE id = $1`
+	_, err := database.DB.ExecContext(ctx, query, userID, notice)
+	if err != nil {
+		return fmt.Errorf("failed to suspend user: %w", err)
+	}
+	return nil
+}
+
+// ConfirmUser marks a user's account as confirmed and active, clearing
+// any prior suspension notice.
+func ConfirmUser(ctx context.Context, userID uuid.UUID) error {
+	query := `UPDATE users SET user_type = 'active', suspension_notice = NULL, confirmed_at = NOW(), updated_at = NOW() WHERE id = $1`
+	_, err := database.DB.ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to confirm user: %w", err)
+	}
+	return nil
+}
+
 // DeactivateUser marks a user as inactive
 func DeactivateUser(ctx context.Context, userID uuid.UUID) error {
 	query := `UPDATE users SET is_active = false, updated_at = NOW() WHERE id = $1`